@@ -1,12 +1,24 @@
 package main
 
 import (
+	"bytes"
+	"compress/gzip"
+	"crypto/ecdsa"
 	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/hyperledger/fabric-chaincode-go/v2/pkg/cid"
 	"github.com/hyperledger/fabric-contract-api-go/v2/contractapi"
 )
 
@@ -31,6 +43,54 @@ type Credential struct {
 	Status        string `json:"status"`        // issued | revoked
 	OwnerMSP      string `json:"ownerMSP"`
 	SharedWithMSP string `json:"sharedWithMSP"` // required, may be ""
+	JWT           string `json:"jwt,omitempty"` // detached VC-JWT, set once issued via IssueVerifiableCredential
+	MerkleRoot    string `json:"merkleRoot"`    // root of the per-field disclosure tree; see GenerateDisclosureProof
+	ListID        string `json:"listID"`        // StatusList2021 list this credential's revocation bit lives in
+	ListIndex     int    `json:"listIndex"`     // bit position within ListID's bitmap
+}
+
+// ListAssignment records which revocation-list bit a credential owns, kept
+// in public state under credListKey(credID) (a hash of credID, not credID
+// itself) so off-chain verifiers can look it up without needing access to
+// either org's private collection, while a caller who doesn't already know
+// credID can't recover it by range-scanning the "credlist/" prefix.
+type ListAssignment struct {
+	ListID string `json:"listID"`
+	Index  int    `json:"index"`
+}
+
+// RevocationStatus is the result of GetRevocationStatus.
+type RevocationStatus struct {
+	ListID  string `json:"listID"`
+	Index   int    `json:"index"`
+	Revoked bool   `json:"revoked"`
+	ListURI string `json:"listURI"`
+}
+
+// revListCounter tracks the active StatusList2021 list and the next free
+// bit within it, stored in public state under "revlistcounter".
+type revListCounter struct {
+	ListID    string `json:"listID"`
+	NextIndex int    `json:"nextIndex"`
+}
+
+// VerifiableCredential is the W3C Verifiable Credential JSON-LD view of a
+// Credential, as returned by IssueVerifiableCredential and embedded in the
+// "vc" claim of the JWT form.
+type VerifiableCredential struct {
+	Context           []string `json:"@context"`
+	Type              []string `json:"type"`
+	Issuer            string   `json:"issuer"`
+	IssuanceDate      string   `json:"issuanceDate"`
+	CredentialSubject VerifiableCredentialSubject `json:"credentialSubject"`
+}
+
+type VerifiableCredentialSubject struct {
+	ID          string `json:"id"`
+	StudentName string `json:"studentName"`
+	Degree      string `json:"degree"`
+	GPA         string `json:"gpa"`
+	University  string `json:"university"`
 }
 
 type IntegrityReport struct {
@@ -43,11 +103,126 @@ type IntegrityReport struct {
 }
 
 type AuditEvent struct {
-	TxID      string `json:"txID"`
-	Action    string `json:"action"`   // ISSUE | SHARE_TO_ORG2 | REVOKE
-	MSPID     string `json:"mspID"`
-	Timestamp string `json:"timestamp"` // RFC3339
-	Note      string `json:"note"`      // REQUIRED (always present; empty string is fine)
+	TxID      string   `json:"txID"`
+	CredID    string   `json:"credID"`
+	Action    string   `json:"action"`   // ISSUE | SHARE_TO_ORG2 | REVOKE | REVLIST_UPDATE
+	MSPID     string   `json:"mspID"`
+	Timestamp string   `json:"timestamp"` // RFC3339
+	Note      string   `json:"note"`      // REQUIRED (always present; empty string is fine)
+	Topics    []string `json:"topics,omitempty"` // labels fixed per action by the chaincode ("org2-shared" on SHARE_TO_ORG2, "revoked" on REVOKE/REVLIST_UPDATE, none on ISSUE); callers cannot set them
+}
+
+// AuditFilter scopes a QueryAuditEvents call. All fields are optional;
+// an unset field does not filter on that dimension. FromTimestamp and
+// ToTimestamp are RFC3339.
+type AuditFilter struct {
+	Action        string   `json:"action,omitempty"`
+	MSPID         string   `json:"mspID,omitempty"`
+	FromTimestamp string   `json:"fromTimestamp,omitempty"`
+	ToTimestamp   string   `json:"toTimestamp,omitempty"`
+	CredIDs       []string `json:"credIDs,omitempty"`
+	Topics        []string `json:"topics,omitempty"`
+	PageSize      int32    `json:"pageSize"`
+	Bookmark      string   `json:"bookmark"`
+}
+
+// AuditEventPage is one page of QueryAuditEvents results.
+type AuditEventPage struct {
+	Events   []*AuditEvent `json:"events"`
+	Bookmark string        `json:"bookmark"`
+}
+
+// RevealedField is one disclosed leaf of a DisclosureProof: the plaintext
+// field value plus the salt needed to recompute its leaf hash.
+type RevealedField struct {
+	Value string `json:"value"`
+	Salt  string `json:"salt"`
+}
+
+// MerkleSibling is one undisclosed leaf of a DisclosureProof, carried as
+// its already-hashed leaf (no value or salt) so the verifier can still
+// rebuild the full leaf set without learning the field's contents.
+type MerkleSibling struct {
+	Index int    `json:"index"`
+	Hash  string `json:"hash"`
+}
+
+// DisclosureProof lets Org1 reveal a subset of a Credential's fields to
+// Org2 while proving they belong to the credential committed at Root.
+type DisclosureProof struct {
+	Root     string                   `json:"root"`
+	Revealed map[string]RevealedField `json:"revealed"`
+	Siblings []MerkleSibling          `json:"siblings"`
+}
+
+// DisclosureVerification is the result of VerifyDisclosureProof.
+type DisclosureVerification struct {
+	CredID   string            `json:"credID"`
+	Valid    bool              `json:"valid"`
+	Revealed map[string]string `json:"revealed"`
+}
+
+// NameRecord binds a human-readable alias (e.g. "mit/cs/2024/alice") to a
+// credID, stored in public state under "name/<name>".
+type NameRecord struct {
+	Name       string `json:"name"`
+	CredID     string `json:"credID"`
+	OwnerMSP   string `json:"ownerMSP"`
+	ExpiryTime uint64 `json:"expiryTime"` // Unix seconds at which an uncontested reservation becomes final
+}
+
+// NameAuction is the sealed-bid (commit-reveal) auction opened when a
+// second MSP calls BidOnName on a still-contestable name, stored under
+// "auction/<name>". Bids are genuinely sealed: BidOnName only ever records
+// a hash commitment, so no bidder — including the auction's own
+// participants — can see another's amount before the commit phase closes;
+// RevealBid is what actually discloses an amount, and only after CloseTime.
+type NameAuction struct {
+	Name           string            `json:"name"`
+	CloseTime      uint64            `json:"closeTime"`      // Unix seconds the commit phase ends and reveals open
+	RevealDeadline uint64            `json:"revealDeadline"` // Unix seconds the reveal phase ends; CommitNameAuction settles after this
+	Commitments    map[string][]byte `json:"commitments"`    // bidder MSP -> sha256(amount|salt|bidderMSP)
+	Revealed       map[string]uint64 `json:"revealed"`       // bidder MSP -> amount, once RevealBid verifies its commitment
+	HighestBidMSP  string            `json:"highestBidMSP"`
+	HighestBid     uint64            `json:"highestBid"`
+}
+
+// Checkpoint is a signed, periodic snapshot of every issued credential's
+// (credID, hash, status) as a Merkle root, stored under "ckpt/<index>".
+// Light clients can verify a single credential against Root instead of
+// replaying the full ledger or trusting Org2's private-data copy.
+type Checkpoint struct {
+	Index       uint64   `json:"index"`
+	Root        string   `json:"root"`
+	BlockHeight uint64   `json:"blockHeight"` // Unix seconds of the proposing transaction (ledgerClock), not a block number
+	Timestamp   string   `json:"timestamp"`
+	SignersMSP  []string `json:"signersMSP"`
+}
+
+// pendingCheckpoint accumulates attestations from designatedCheckpointSigners
+// for a not-yet-finalized checkpoint, stored under "ckptpending" until
+// checkpointThreshold signers have countersigned.
+type pendingCheckpoint struct {
+	Index       uint64            `json:"index"`
+	Root        string            `json:"root"`
+	BlockHeight uint64            `json:"blockHeight"`
+	Signatures  map[string][]byte `json:"signatures"`
+}
+
+// MerkleProofStep is one sibling hash on a Merkle audit path; Left records
+// which side of the pairwise hash the sibling sits on.
+type MerkleProofStep struct {
+	Hash string `json:"hash"`
+	Left bool   `json:"left"`
+}
+
+// InclusionProof is the audit path proving CredID's Leaf was committed by
+// the checkpoint whose root is Root.
+type InclusionProof struct {
+	CredID string            `json:"credID"`
+	Leaf   string            `json:"leaf"`
+	Path   []MerkleProofStep `json:"path"`
+	Root   string            `json:"root"`
 }
 
 // ==============================
@@ -73,6 +248,369 @@ func sha256Hex(s string) string {
 	return hex.EncodeToString(sum[:])
 }
 
+// disclosureFieldOrder fixes the leaf order of the per-field Merkle tree
+// built for selective disclosure; it mirrors the fields canonicalString
+// hashes over.
+var disclosureFieldOrder = []string{
+	"credID", "studentID", "studentName", "university", "degree", "gpa", "issueDate",
+}
+
+// disclosureFieldValue returns c's value for one of disclosureFieldOrder's
+// names, or ok=false if name is unknown.
+func disclosureFieldValue(c *Credential, name string) (value string, ok bool) {
+	switch name {
+	case "credID":
+		return c.CredID, true
+	case "studentID":
+		return c.StudentID, true
+	case "studentName":
+		return c.StudentName, true
+	case "university":
+		return c.University, true
+	case "degree":
+		return c.Degree, true
+	case "gpa":
+		return c.GPA, true
+	case "issueDate":
+		return c.IssueDate, true
+	default:
+		return "", false
+	}
+}
+
+// disclosureFieldIndex returns name's position in disclosureFieldOrder, or
+// -1 if name is not a recognized field.
+func disclosureFieldIndex(name string) int {
+	for i, n := range disclosureFieldOrder {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// disclosureLeafHash hashes one salted field leaf: H(fieldName||fieldValue||salt).
+func disclosureLeafHash(name, value, salt string) string {
+	return sha256Hex(name + "|" + value + "|" + salt)
+}
+
+// merkleRoot computes a binary Merkle root over leaves, duplicating the
+// last node at each level when the level has odd length.
+func merkleRoot(leaves []string) string {
+	if len(leaves) == 0 {
+		return ""
+	}
+	level := append([]string(nil), leaves...)
+	for len(level) > 1 {
+		next := make([]string, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 < len(level) {
+				next = append(next, sha256Hex(level[i]+level[i+1]))
+			} else {
+				next = append(next, sha256Hex(level[i]+level[i]))
+			}
+		}
+		level = next
+	}
+	return level[0]
+}
+
+// disclosureSaltsFromTransient reads the per-field selective-disclosure
+// salts the caller generated off-chain out of the transaction's transient
+// map (key "fieldSalts", a JSON object of disclosureFieldOrder name ->
+// salt). Unlike a value derived from TxID or any other field recorded in a
+// block, transient data is never written to the ledger or gossiped past
+// the endorsing peer, so these salts can't be recovered later by reading
+// public chaincode events or audit history — only private collections ever
+// hold them: Org1's PDC, where IssueCredential persists them under
+// "salts/<credID>", and Org2's PDC, where StoreCredentialForOrg2 stores the
+// copy it is handed under the same key.
+func disclosureSaltsFromTransient(ctx contractapi.TransactionContextInterface) (map[string]string, error) {
+	transient, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return nil, fmt.Errorf("get transient: %w", err)
+	}
+	raw, ok := transient["fieldSalts"]
+	if !ok {
+		return nil, fmt.Errorf("transient field \"fieldSalts\" is required")
+	}
+	var salts map[string]string
+	if err := json.Unmarshal(raw, &salts); err != nil {
+		return nil, fmt.Errorf("unmarshal transient fieldSalts: %w", err)
+	}
+	for _, name := range disclosureFieldOrder {
+		if salts[name] == "" {
+			return nil, fmt.Errorf("transient fieldSalts missing salt for %q", name)
+		}
+	}
+	return salts, nil
+}
+
+// disclosureRoot computes the Merkle root of cred over all fields using salts.
+func disclosureRoot(cred *Credential, salts map[string]string) string {
+	leaves := make([]string, len(disclosureFieldOrder))
+	for i, name := range disclosureFieldOrder {
+		value, _ := disclosureFieldValue(cred, name)
+		leaves[i] = disclosureLeafHash(name, value, salts[name])
+	}
+	return merkleRoot(leaves)
+}
+
+// issuerDID derives a stable DID for an issuing org from its MSP ID and the
+// submitting identity's certificate.
+func issuerDID(mspID string, cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return fmt.Sprintf("did:fabric:%s:%s", strings.ToLower(mspID), hex.EncodeToString(sum[:8]))
+}
+
+// studentDID derives a DID for the credential subject from the studentID.
+func studentDID(studentID string) string {
+	return "did:fabric:student:" + studentID
+}
+
+// jwtSigningInput builds the JWT's compact "header.payload" signing input
+// (RFC 7519, alg ES256) for vc. It is pure and deterministic given cred,
+// vc and the current transaction's timestamp/TxID, so the client preparing
+// a VC off-chain can compute byte-identical input to sign before ever
+// submitting IssueVerifiableCredential.
+func jwtSigningInput(ctx contractapi.TransactionContextInterface, cred *Credential, vc *VerifiableCredential) (string, error) {
+	header, _ := json.Marshal(map[string]string{"alg": "ES256", "typ": "JWT"})
+
+	ts, err := ctx.GetStub().GetTxTimestamp()
+	if err != nil {
+		return "", fmt.Errorf("get tx timestamp: %w", err)
+	}
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iss": vc.Issuer,
+		"sub": vc.CredentialSubject.ID,
+		"nbf": ts.GetSeconds(),
+		"jti": cred.CredID,
+		"vc":  vc,
+	})
+	return base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims), nil
+}
+
+// verifyDetachedJWTSignature checks sigB64 (a raw R||S ECDSA signature,
+// base64url-encoded, 64 bytes) over signingInput against cert's public key.
+//
+// Chaincode never has access to a client's real private key, so it cannot
+// sign on an issuer's behalf: the issuer signs signingInput off-chain with
+// their actual key before submitting the transaction, and this function's
+// only job is to verify that detached signature against the certificate
+// already on file — it never derives or fabricates a signing key itself.
+func verifyDetachedJWTSignature(cert *x509.Certificate, signingInput, sigB64 string) error {
+	pub, ok := cert.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("issuer certificate does not use an ECDSA public key")
+	}
+	sigB, err := base64.RawURLEncoding.DecodeString(sigB64)
+	if err != nil || len(sigB) != 64 {
+		return fmt.Errorf("malformed JWT signature")
+	}
+	digest := sha256.Sum256([]byte(signingInput))
+	r := new(big.Int).SetBytes(sigB[:32])
+	s := new(big.Int).SetBytes(sigB[32:])
+	if !ecdsa.Verify(pub, digest[:], r, s) {
+		return fmt.Errorf("JWT signature verification failed")
+	}
+	return nil
+}
+
+// revListCapacityBits is the fixed size of each StatusList2021-style bitmap.
+const revListCapacityBits = 131072
+
+// setBit flips bit index on within bitmap (LSB-first within each byte).
+func setBit(bitmap []byte, index int) {
+	bitmap[index/8] |= 1 << uint(index%8)
+}
+
+// getBit reports whether bit index is set within bitmap.
+func getBit(bitmap []byte, index int) bool {
+	return bitmap[index/8]&(1<<uint(index%8)) != 0
+}
+
+// encodeBitmap gzip-compresses and base64-encodes bitmap for storage as a
+// public state value.
+func encodeBitmap(bitmap []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(bitmap); err != nil {
+		return nil, fmt.Errorf("gzip revocation list: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("close gzip writer: %w", err)
+	}
+	return []byte(base64.StdEncoding.EncodeToString(buf.Bytes())), nil
+}
+
+// decodeBitmap reverses encodeBitmap.
+func decodeBitmap(stored []byte) ([]byte, error) {
+	compressed, err := base64.StdEncoding.DecodeString(string(stored))
+	if err != nil {
+		return nil, fmt.Errorf("decode revocation list: %w", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("gunzip revocation list: %w", err)
+	}
+	defer zr.Close()
+	out, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("read revocation list: %w", err)
+	}
+	return out, nil
+}
+
+// loadBitmap reads listID's bitmap from public state, returning an
+// all-clear bitmap if the list hasn't been published yet.
+func loadBitmap(ctx contractapi.TransactionContextInterface, listID string) ([]byte, error) {
+	raw, err := ctx.GetStub().GetState("revlist/" + listID)
+	if err != nil {
+		return nil, fmt.Errorf("get state (revlist): %w", err)
+	}
+	if raw == nil {
+		return make([]byte, revListCapacityBits/8), nil
+	}
+	return decodeBitmap(raw)
+}
+
+// nextListAssignment hands out the next free (listID, index) bit, rolling
+// over to a new list once the active one fills up.
+func nextListAssignment(ctx contractapi.TransactionContextInterface) (ListAssignment, error) {
+	raw, err := ctx.GetStub().GetState("revlistcounter")
+	if err != nil {
+		return ListAssignment{}, fmt.Errorf("get state (revlist counter): %w", err)
+	}
+	counter := revListCounter{ListID: "1", NextIndex: 0}
+	if raw != nil {
+		if err := json.Unmarshal(raw, &counter); err != nil {
+			return ListAssignment{}, fmt.Errorf("unmarshal revlist counter: %w", err)
+		}
+	}
+	if counter.NextIndex >= revListCapacityBits {
+		n, _ := strconv.Atoi(counter.ListID)
+		counter.ListID = strconv.Itoa(n + 1)
+		counter.NextIndex = 0
+	}
+
+	assignment := ListAssignment{ListID: counter.ListID, Index: counter.NextIndex}
+	counter.NextIndex++
+
+	b, _ := json.Marshal(counter)
+	if err := ctx.GetStub().PutState("revlistcounter", b); err != nil {
+		return ListAssignment{}, fmt.Errorf("put state (revlist counter): %w", err)
+	}
+	return assignment, nil
+}
+
+const (
+	nameContestWindowSeconds = 600 // seconds after ReserveName before an uncontested name becomes final
+	nameAuctionWindowSeconds = 300 // seconds the commit phase stays open once the first bid commitment lands
+	nameRevealWindowSeconds  = 300 // seconds bidders have to reveal once the commit phase closes
+)
+
+// credListKey is where a credential's ListAssignment lives in public state.
+// Keying by sha256(credID) rather than credID itself means a range scan over
+// the "credlist/" prefix surfaces only opaque digests: recovering a credID
+// (and with it issuance volume/timing) requires already knowing it, exactly
+// as GetRevocationStatus's own credID parameter does.
+func credListKey(credID string) string       { return "credlist/" + sha256Hex(credID) }
+func nameKey(name string) string             { return "name/" + name }
+func nameAuctionKey(name string) string      { return "auction/" + name }
+func credNameKey(credID, name string) string { return "credname/" + credID + "/" + name }
+func credNamePrefix(credID string) string    { return "credname/" + credID + "/" }
+
+// ledgerClock returns the Unix-seconds timestamp ctx.GetStub().GetTxTimestamp
+// assigns this transaction: a wall-clock value every endorsing peer agrees
+// on deterministically. Naming deadlines (ExpiryTime, CloseTime) are
+// measured against it rather than a self-inflicted public-state counter —
+// a counter that only advances on naming calls themselves can be
+// race-advanced by anyone spamming cheap ReserveName/BidOnName calls to
+// prematurely finalize a reservation or force an auction closed.
+func ledgerClock(ctx contractapi.TransactionContextInterface) (uint64, error) {
+	ts, err := ctx.GetStub().GetTxTimestamp()
+	if err != nil {
+		return 0, fmt.Errorf("get tx timestamp: %w", err)
+	}
+	return uint64(ts.GetSeconds()), nil
+}
+
+// designatedCheckpointSigners are the MSPs whose countersignatures
+// PublishCheckpoint collects before a proposed checkpoint root is accepted.
+var designatedCheckpointSigners = []string{"Org1MSP", "Org2MSP"}
+
+// checkpointThreshold is the M in "M-of-N" designatedCheckpointSigners
+// required to countersign a checkpoint before it is finalized.
+const checkpointThreshold = 2
+
+// isDesignatedCheckpointSigner reports whether msp is one of
+// designatedCheckpointSigners.
+func isDesignatedCheckpointSigner(msp string) bool {
+	for _, m := range designatedCheckpointSigners {
+		if m == msp {
+			return true
+		}
+	}
+	return false
+}
+
+// checkpointLeafHash hashes one checkpoint leaf: H(credID||canonicalHash||status).
+func checkpointLeafHash(credID, canonicalHash, status string) string {
+	return sha256Hex(credID + "|" + canonicalHash + "|" + status)
+}
+
+// nextCheckpointIndex returns the next unused checkpoint index and advances
+// the public-state counter backing it.
+func nextCheckpointIndex(ctx contractapi.TransactionContextInterface) (uint64, error) {
+	raw, err := ctx.GetStub().GetState("ckptindex")
+	if err != nil {
+		return 0, fmt.Errorf("get state (checkpoint index): %w", err)
+	}
+	idx := uint64(0)
+	if raw != nil {
+		idx, err = strconv.ParseUint(string(raw), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("parse checkpoint index: %w", err)
+		}
+	}
+	if err := ctx.GetStub().PutState("ckptindex", []byte(strconv.FormatUint(idx+1, 10))); err != nil {
+		return 0, fmt.Errorf("put state (checkpoint index): %w", err)
+	}
+	return idx, nil
+}
+
+// merkleProof returns the audit path from leaves[index] up to the root, plus
+// the recomputed root itself. It mirrors merkleRoot's pairing/duplication
+// rule level by level so the two always agree.
+func merkleProof(leaves []string, index int) ([]MerkleProofStep, string) {
+	level := append([]string(nil), leaves...)
+	path := make([]MerkleProofStep, 0)
+	for len(level) > 1 {
+		next := make([]string, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			left := level[i]
+			right := left
+			if i+1 < len(level) {
+				right = level[i+1]
+			}
+			next = append(next, sha256Hex(left+right))
+			if i == index {
+				path = append(path, MerkleProofStep{Hash: right, Left: false})
+				index = len(next) - 1
+			} else if i+1 == index {
+				path = append(path, MerkleProofStep{Hash: left, Left: true})
+				index = len(next) - 1
+			}
+		}
+		level = next
+	}
+	root := ""
+	if len(level) == 1 {
+		root = level[0]
+	}
+	return path, root
+}
+
 func (s *SmartContract) putPDC(ctx contractapi.TransactionContextInterface, collection, key string, val []byte) error {
 	if err := ctx.GetStub().PutPrivateData(collection, key, val); err != nil {
 		return fmt.Errorf("put private data (%s/%s): %w", collection, key, err)
@@ -88,7 +626,7 @@ func (s *SmartContract) getPDC(ctx contractapi.TransactionContextInterface, coll
 	return val, nil
 }
 
-func (s *SmartContract) putAudit(ctx contractapi.TransactionContextInterface, credID, action, note string) error {
+func (s *SmartContract) putAudit(ctx contractapi.TransactionContextInterface, credID, action, note string, topics []string) (*AuditEvent, error) {
 	txID := ctx.GetStub().GetTxID()
 	ts, _ := ctx.GetStub().GetTxTimestamp()
 	t := time.Unix(ts.GetSeconds(), int64(ts.GetNanos())).UTC().Format(time.RFC3339)
@@ -96,23 +634,79 @@ func (s *SmartContract) putAudit(ctx contractapi.TransactionContextInterface, cr
 	msp, _ := ctx.GetClientIdentity().GetMSPID()
 	ev := AuditEvent{
 		TxID:      txID,
+		CredID:    credID,
 		Action:    action,
 		MSPID:     msp,
 		Timestamp: t,
 		Note:      note, // always present (can be "")
+		Topics:    topics,
 	}
 	b, _ := json.Marshal(ev)
 
 	key, err := ctx.GetStub().CreateCompositeKey("evt", []string{credID, txID})
 	if err != nil {
-		return fmt.Errorf("create composite key: %w", err)
+		return nil, fmt.Errorf("create composite key: %w", err)
 	}
 	if err := ctx.GetStub().PutState(key, b); err != nil {
-		return fmt.Errorf("put state (audit): %w", err)
+		return nil, fmt.Errorf("put state (audit): %w", err)
+	}
+	return &ev, nil
+}
+
+// emitAuditEvent publishes ev as a Fabric chaincode event named
+// "academic.credential.<action>" so an off-chain consumer can subscribe via
+// the SDK's block-event listener instead of polling ListHistory or
+// QueryAuditEvents. A transaction that writes more than one audit record
+// (e.g. RevokeCredential) must use emitAuditEvents instead, so every
+// recorded action still reaches subscribers.
+func (s *SmartContract) emitAuditEvent(ctx contractapi.TransactionContextInterface, ev *AuditEvent) error {
+	return s.emitAuditEvents(ctx, ev)
+}
+
+// emitAuditEvents publishes every audit record a transaction wrote as a
+// single Fabric chaincode event, since GetStub().SetEvent may only be
+// called once per transaction. A transaction with a single record keeps
+// the specific "academic.credential.<action>" event name; one with several
+// (e.g. RevokeCredential's REVLIST_UPDATE followed by REVOKE) is published
+// as "academic.credential.batch" carrying all of them, so a subscriber
+// wanting e.g. REVLIST_UPDATE can inspect each entry's Action field instead
+// of only ever seeing whichever action happened to run last.
+func (s *SmartContract) emitAuditEvents(ctx contractapi.TransactionContextInterface, evs ...*AuditEvent) error {
+	if len(evs) == 0 {
+		return fmt.Errorf("emitAuditEvents: no events given")
+	}
+	name := "academic.credential.batch"
+	var payload interface{} = evs
+	if len(evs) == 1 {
+		name = "academic.credential." + evs[0].Action
+		payload = evs[0]
+	}
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal audit event: %w", err)
+	}
+	if err := ctx.GetStub().SetEvent(name, b); err != nil {
+		return fmt.Errorf("set event: %w", err)
 	}
 	return nil
 }
 
+// FilterByTopic reports whether ev carries any of the given topics. An
+// empty topics list matches everything, so callers can use it unconditionally.
+func FilterByTopic(ev *AuditEvent, topics []string) bool {
+	if len(topics) == 0 {
+		return true
+	}
+	for _, want := range topics {
+		for _, got := range ev.Topics {
+			if got == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // ==============================
 //            Queries
 // ==============================
@@ -144,6 +738,83 @@ func (s *SmartContract) ListHistory(ctx contractapi.TransactionContextInterface,
 	return out, nil
 }
 
+// QueryAuditEvents is ListHistory's filterable, paginated counterpart: it
+// scans the "evt" namespace (scoped to a single credential when filter.CredIDs
+// names exactly one, since GetStateByPartialCompositeKeyWithPagination only
+// supports a fixed key prefix) and applies Action, MSPID, timestamp range,
+// CredIDs and Topics as in-memory filters over that page.
+func (s *SmartContract) QueryAuditEvents(ctx contractapi.TransactionContextInterface, filter AuditFilter) (*AuditEventPage, error) {
+	partialKey := []string{}
+	if len(filter.CredIDs) == 1 {
+		partialKey = []string{filter.CredIDs[0]}
+	}
+	pageSize := filter.PageSize
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+
+	iter, meta, err := ctx.GetStub().GetStateByPartialCompositeKeyWithPagination("evt", partialKey, pageSize, filter.Bookmark)
+	if err != nil {
+		return nil, fmt.Errorf("audit query: %w", err)
+	}
+	defer iter.Close()
+
+	var from, to time.Time
+	if filter.FromTimestamp != "" {
+		if from, err = time.Parse(time.RFC3339, filter.FromTimestamp); err != nil {
+			return nil, fmt.Errorf("parse fromTimestamp: %w", err)
+		}
+	}
+	if filter.ToTimestamp != "" {
+		if to, err = time.Parse(time.RFC3339, filter.ToTimestamp); err != nil {
+			return nil, fmt.Errorf("parse toTimestamp: %w", err)
+		}
+	}
+	credIDs := make(map[string]bool, len(filter.CredIDs))
+	for _, id := range filter.CredIDs {
+		credIDs[id] = true
+	}
+
+	out := make([]*AuditEvent, 0)
+	for iter.HasNext() {
+		kv, err := iter.Next()
+		if err != nil {
+			return nil, fmt.Errorf("audit iterate: %w", err)
+		}
+		var ev AuditEvent
+		if err := json.Unmarshal(kv.Value, &ev); err != nil {
+			continue
+		}
+		if filter.Action != "" && ev.Action != filter.Action {
+			continue
+		}
+		if filter.MSPID != "" && ev.MSPID != filter.MSPID {
+			continue
+		}
+		if len(credIDs) > 0 && !credIDs[ev.CredID] {
+			continue
+		}
+		if !FilterByTopic(&ev, filter.Topics) {
+			continue
+		}
+		if !from.IsZero() || !to.IsZero() {
+			evTime, err := time.Parse(time.RFC3339, ev.Timestamp)
+			if err != nil {
+				continue
+			}
+			if !from.IsZero() && evTime.Before(from) {
+				continue
+			}
+			if !to.IsZero() && evTime.After(to) {
+				continue
+			}
+		}
+		out = append(out, &ev)
+	}
+
+	return &AuditEventPage{Events: out, Bookmark: meta.GetBookmark()}, nil
+}
+
 // ReadCredential returns Org1’s private record.
 func (s *SmartContract) ReadCredential(ctx contractapi.TransactionContextInterface, credID string) (*Credential, error) {
 	raw, err := s.getPDC(ctx, org1PDC, credID)
@@ -187,7 +858,11 @@ func (s *SmartContract) VerifyCredential(ctx contractapi.TransactionContextInter
 	return &cred, nil
 }
 
-// VerifyCredentialIntegrity recomputes the canonical hash for Org2’s view.
+// VerifyCredentialIntegrity recomputes the fully-revealed selective-
+// disclosure Merkle root over Org2's private copy and checks it against
+// the committed MerkleRoot. This is the "every field revealed" case of
+// VerifyDisclosureProof, and replaces the old flat SHA-256 comparison as
+// the record's integrity check.
 func (s *SmartContract) VerifyCredentialIntegrity(ctx contractapi.TransactionContextInterface, credID string) (*IntegrityReport, error) {
 	msp, _ := ctx.GetClientIdentity().GetMSPID()
 	if msp != "Org2MSP" {
@@ -204,99 +879,578 @@ func (s *SmartContract) VerifyCredentialIntegrity(ctx contractapi.TransactionCon
 	if err := json.Unmarshal(raw, &cred); err != nil {
 		return nil, fmt.Errorf("unmarshal credential: %w", err)
 	}
-	computed := sha256Hex(canonicalString(&cred))
+
+	saltsRaw, err := s.getPDC(ctx, org2PDC, "salts/"+credID)
+	if err != nil {
+		return nil, err
+	}
+	if saltsRaw == nil {
+		return nil, fmt.Errorf("disclosure salts for %s not found in Org2 collection", credID)
+	}
+	var salts map[string]string
+	if err := json.Unmarshal(saltsRaw, &salts); err != nil {
+		return nil, fmt.Errorf("unmarshal disclosure salts: %w", err)
+	}
+
+	computed := disclosureRoot(&cred, salts)
 	return &IntegrityReport{
 		CredID:        cred.CredID,
-		StoredHash:    cred.Hash,
+		StoredHash:    cred.MerkleRoot,
 		ComputedHash:  computed,
-		IsHashValid:   cred.Hash == computed,
+		IsHashValid:   cred.MerkleRoot == computed,
 		SharedWithMSP: cred.SharedWithMSP,
 		Status:        cred.Status,
 	}, nil
 }
 
-// ==============================
-//        Transactions
-// ==============================
-
-// IssueCredential creates Org1’s private record with an auto-computed hash.
-func (s *SmartContract) IssueCredential(ctx contractapi.TransactionContextInterface,
-	credID, studentID, studentName, university, degree, gpa, issueDate, _ string) error {
-
-	if credID == "" {
-		return fmt.Errorf("credID is required")
+// VerifyVC validates the detached JWT on Org2's private copy of credID
+// against the issuer's on-chain certificate and returns the decoded
+// Verifiable Credential. This is the standards-based counterpart to
+// VerifyCredentialIntegrity's Merkle-root check.
+func (s *SmartContract) VerifyVC(ctx contractapi.TransactionContextInterface, credID string) (*VerifiableCredential, error) {
+	cred, err := s.VerifyCredential(ctx, credID)
+	if err != nil {
+		return nil, err
 	}
-	exists, err := s.CredentialExists(ctx, credID)
+	// Org2's private copy is only ever refreshed by StoreCredentialForOrg2, so
+	// its Status field goes stale the moment RevokeCredential runs (which only
+	// touches org1PDC and the public revocation bitmap). Check the live bit
+	// instead of trusting the copy.
+	revoked, err := s.IsRevoked(ctx, cred.ListID, cred.ListIndex)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if exists {
-		return fmt.Errorf("credential %s already exists", credID)
+	if revoked {
+		return nil, fmt.Errorf("credential %s is revoked", credID)
 	}
-
-	msp, err := ctx.GetClientIdentity().GetMSPID()
-	if err != nil {
-		return fmt.Errorf("get MSP ID: %w", err)
+	if cred.JWT == "" {
+		return nil, fmt.Errorf("credential %s has no JWT representation", credID)
 	}
 
-	cred := Credential{
-		CredID:        credID,
-		StudentID:     studentID,
-		StudentName:   studentName,
-		University:    university,
-		Degree:        degree,
-		GPA:           gpa,
-		IssueDate:     issueDate,
-		Status:        "issued",
-		OwnerMSP:      msp,
-		SharedWithMSP: "",
+	parts := strings.Split(cred.JWT, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed JWT for %s", credID)
 	}
-	cred.Hash = sha256Hex(canonicalString(&cred))
 
-	b, _ := json.Marshal(cred)
-	if err := s.putPDC(ctx, org1PDC, credID, b); err != nil {
-		return err
+	certPEM, err := ctx.GetStub().GetState("issuercert/" + cred.OwnerMSP)
+	if err != nil {
+		return nil, fmt.Errorf("get state (issuer cert): %w", err)
 	}
-	return s.putAudit(ctx, credID, "ISSUE", "")
-}
-
-// StoreCredentialForOrg2 upserts Org2’s private copy (Org2 identity required).
-// It enforces that the provided hash matches the recomputed canonical hash.
-func (s *SmartContract) StoreCredentialForOrg2(ctx contractapi.TransactionContextInterface, credJSON string) error {
-	msp, _ := ctx.GetClientIdentity().GetMSPID()
-	if msp != "Org2MSP" {
-		return fmt.Errorf("only Org2 can write into %s", org2PDC)
+	if certPEM == nil {
+		return nil, fmt.Errorf("issuer certificate for %s not found", cred.OwnerMSP)
 	}
-	var cred Credential
-	if err := json.Unmarshal([]byte(credJSON), &cred); err != nil {
-		return fmt.Errorf("invalid credential json: %w", err)
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return nil, fmt.Errorf("decode issuer certificate: invalid PEM")
 	}
-	if cred.CredID == "" {
-		return fmt.Errorf("credID required in credential json")
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse issuer certificate: %w", err)
 	}
 
-	computed := sha256Hex(canonicalString(&cred))
-	if cred.Hash == "" || cred.Hash != computed {
-		return fmt.Errorf("hash mismatch for %s: provided='%s' computed='%s'", cred.CredID, cred.Hash, computed)
+	if err := verifyDetachedJWTSignature(cert, parts[0]+"."+parts[1], parts[2]); err != nil {
+		return nil, fmt.Errorf("%w for %s", err, credID)
 	}
 
-	cred.SharedWithMSP = "Org2MSP"
-	b, _ := json.Marshal(cred)
-	if err := s.putPDC(ctx, org2PDC, cred.CredID, b); err != nil {
-		return err
+	claimsB, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("decode JWT claims: %w", err)
+	}
+	var claims struct {
+		VC *VerifiableCredential `json:"vc"`
+	}
+	if err := json.Unmarshal(claimsB, &claims); err != nil {
+		return nil, fmt.Errorf("unmarshal JWT claims: %w", err)
 	}
-	return s.putAudit(ctx, cred.CredID, "SHARE_TO_ORG2", "")
+	return claims.VC, nil
 }
 
-// RevokeCredential updates Org1’s private record status to "revoked".
-func (s *SmartContract) RevokeCredential(ctx contractapi.TransactionContextInterface, credID string) error {
+// GenerateDisclosureProof lets Org1 prove that a chosen subset of credID's
+// fields belong to the credential committed at MerkleRoot, without
+// revealing the rest. Undisclosed fields are carried only as their leaf
+// hash in Siblings, never their value or salt.
+func (s *SmartContract) GenerateDisclosureProof(ctx contractapi.TransactionContextInterface, credID string, attributesToReveal []string) (*DisclosureProof, error) {
 	msp, _ := ctx.GetClientIdentity().GetMSPID()
 	if msp != "Org1MSP" {
-		return fmt.Errorf("only Org1 can revoke credentials")
+		return nil, fmt.Errorf("only Org1 can generate disclosure proofs")
 	}
+
 	raw, err := s.getPDC(ctx, org1PDC, credID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("credential %s not found", credID)
+	}
+	var cred Credential
+	if err := json.Unmarshal(raw, &cred); err != nil {
+		return nil, fmt.Errorf("unmarshal credential: %w", err)
+	}
+
+	saltsRaw, err := s.getPDC(ctx, org1PDC, "salts/"+credID)
+	if err != nil {
+		return nil, err
+	}
+	if saltsRaw == nil {
+		return nil, fmt.Errorf("disclosure salts for %s not found", credID)
+	}
+	var salts map[string]string
+	if err := json.Unmarshal(saltsRaw, &salts); err != nil {
+		return nil, fmt.Errorf("unmarshal disclosure salts: %w", err)
+	}
+
+	reveal := make(map[string]bool, len(attributesToReveal))
+	for _, name := range attributesToReveal {
+		if disclosureFieldIndex(name) < 0 {
+			return nil, fmt.Errorf("unknown field %q", name)
+		}
+		reveal[name] = true
+	}
+
+	revealed := make(map[string]RevealedField, len(reveal))
+	siblings := make([]MerkleSibling, 0, len(disclosureFieldOrder)-len(reveal))
+	for i, name := range disclosureFieldOrder {
+		value, _ := disclosureFieldValue(&cred, name)
+		if reveal[name] {
+			revealed[name] = RevealedField{Value: value, Salt: salts[name]}
+			continue
+		}
+		siblings = append(siblings, MerkleSibling{Index: i, Hash: disclosureLeafHash(name, value, salts[name])})
+	}
+
+	return &DisclosureProof{
+		Root:     cred.MerkleRoot,
+		Revealed: revealed,
+		Siblings: siblings,
+	}, nil
+}
+
+// VerifyDisclosureProof reconstructs the Merkle root from proofJSON's
+// revealed leaves and siblings and checks it against credID's committed
+// MerkleRoot in Org2's private copy. When every field is revealed this is
+// exactly what VerifyCredentialIntegrity checks, minus the need for a
+// caller-supplied proof since Org2 already holds every revealed leaf.
+func (s *SmartContract) VerifyDisclosureProof(ctx contractapi.TransactionContextInterface, credID, proofJSON string) (*DisclosureVerification, error) {
+	msp, _ := ctx.GetClientIdentity().GetMSPID()
+	if msp != "Org2MSP" {
+		return nil, fmt.Errorf("only Org2 can verify disclosure proofs")
+	}
+
+	raw, err := s.getPDC(ctx, org2PDC, credID)
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("credential %s not found in Org2 collection", credID)
+	}
+	var cred Credential
+	if err := json.Unmarshal(raw, &cred); err != nil {
+		return nil, fmt.Errorf("unmarshal credential: %w", err)
+	}
+
+	var proof DisclosureProof
+	if err := json.Unmarshal([]byte(proofJSON), &proof); err != nil {
+		return nil, fmt.Errorf("invalid disclosure proof json: %w", err)
+	}
+
+	leaves := make([]string, len(disclosureFieldOrder))
+	filled := make([]bool, len(disclosureFieldOrder))
+	revealedOut := make(map[string]string, len(proof.Revealed))
+	for name, rf := range proof.Revealed {
+		i := disclosureFieldIndex(name)
+		if i < 0 {
+			return nil, fmt.Errorf("unknown field %q in proof", name)
+		}
+		leaves[i] = disclosureLeafHash(name, rf.Value, rf.Salt)
+		filled[i] = true
+		revealedOut[name] = rf.Value
+	}
+	for _, sib := range proof.Siblings {
+		if sib.Index < 0 || sib.Index >= len(leaves) {
+			return nil, fmt.Errorf("sibling index %d out of range", sib.Index)
+		}
+		leaves[sib.Index] = sib.Hash
+		filled[sib.Index] = true
+	}
+	for i, ok := range filled {
+		if !ok {
+			return nil, fmt.Errorf("incomplete disclosure proof: missing field %q", disclosureFieldOrder[i])
+		}
+	}
+
+	root := merkleRoot(leaves)
+	valid := root == proof.Root && root == cred.MerkleRoot
+	return &DisclosureVerification{
+		CredID:   credID,
+		Valid:    valid,
+		Revealed: revealedOut,
+	}, nil
+}
+
+// GetRevocationStatus looks up credID's StatusList2021 bit from public
+// state, so an off-chain verifier can resolve ListURI once and then check
+// many credentials locally instead of calling chaincode per credential.
+func (s *SmartContract) GetRevocationStatus(ctx contractapi.TransactionContextInterface, credID string) (*RevocationStatus, error) {
+	raw, err := ctx.GetStub().GetState(credListKey(credID))
+	if err != nil {
+		return nil, fmt.Errorf("get state (cred list assignment): %w", err)
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("credential %s has no revocation list assignment", credID)
+	}
+	var assignment ListAssignment
+	if err := json.Unmarshal(raw, &assignment); err != nil {
+		return nil, fmt.Errorf("unmarshal list assignment: %w", err)
+	}
+
+	revoked, err := s.IsRevoked(ctx, assignment.ListID, assignment.Index)
+	if err != nil {
+		return nil, err
+	}
+	return &RevocationStatus{
+		ListID:  assignment.ListID,
+		Index:   assignment.Index,
+		Revoked: revoked,
+		ListURI: "revlist/" + assignment.ListID,
+	}, nil
+}
+
+// IsRevoked checks a single bit of listID's public revocation bitmap.
+func (s *SmartContract) IsRevoked(ctx contractapi.TransactionContextInterface, listID string, index int) (bool, error) {
+	if index < 0 || index >= revListCapacityBits {
+		return false, fmt.Errorf("index %d out of range", index)
+	}
+	bitmap, err := loadBitmap(ctx, listID)
+	if err != nil {
+		return false, err
+	}
+	return getBit(bitmap, index), nil
+}
+
+// ResolveName looks up name's bound credential and returns Org1's private
+// record for it, the same as calling ReadCredential with the resolved
+// credID directly.
+func (s *SmartContract) ResolveName(ctx contractapi.TransactionContextInterface, name string) (*Credential, error) {
+	raw, err := ctx.GetStub().GetState(nameKey(name))
+	if err != nil {
+		return nil, fmt.Errorf("get state (name): %w", err)
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("name %s not found", name)
+	}
+	var record NameRecord
+	if err := json.Unmarshal(raw, &record); err != nil {
+		return nil, fmt.Errorf("unmarshal name record: %w", err)
+	}
+	return s.ReadCredential(ctx, record.CredID)
+}
+
+// ListNamesByOwner returns every name currently owned by msp.
+func (s *SmartContract) ListNamesByOwner(ctx contractapi.TransactionContextInterface, msp string) ([]*NameRecord, error) {
+	iter, err := ctx.GetStub().GetStateByRange("name/", "name/\uffff")
+	if err != nil {
+		return nil, fmt.Errorf("name range query: %w", err)
+	}
+	defer iter.Close()
+
+	out := make([]*NameRecord, 0)
+	for iter.HasNext() {
+		kv, err := iter.Next()
+		if err != nil {
+			return nil, fmt.Errorf("name range iterate: %w", err)
+		}
+		var record NameRecord
+		if err := json.Unmarshal(kv.Value, &record); err != nil {
+			continue
+		}
+		if record.OwnerMSP == msp {
+			out = append(out, &record)
+		}
+	}
+	return out, nil
+}
+
+// GetCheckpoint returns the finalized checkpoint stored under ckpt/<index>.
+func (s *SmartContract) GetCheckpoint(ctx contractapi.TransactionContextInterface, index uint64) (*Checkpoint, error) {
+	raw, err := ctx.GetStub().GetState(fmt.Sprintf("ckpt/%d", index))
+	if err != nil {
+		return nil, fmt.Errorf("get state (checkpoint): %w", err)
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("checkpoint %d not found", index)
+	}
+	var ckpt Checkpoint
+	if err := json.Unmarshal(raw, &ckpt); err != nil {
+		return nil, fmt.Errorf("unmarshal checkpoint: %w", err)
+	}
+	return &ckpt, nil
+}
+
+// ProveInclusion builds a Merkle audit path proving credID was a member of
+// the leaf set PublishCheckpoint committed as checkpointIndex. The leaf set
+// is rebuilt from current ledger state, not replayed from history, so a
+// proof is only produced while that state still hashes to the checkpoint's
+// Root; once any credential has been issued or changed since, the call
+// fails with a message asking for a fresh PublishCheckpoint.
+func (s *SmartContract) ProveInclusion(ctx contractapi.TransactionContextInterface, credID string, checkpointIndex uint64) (*InclusionProof, error) {
+	msp, err := ctx.GetClientIdentity().GetMSPID()
+	if err != nil {
+		return nil, fmt.Errorf("get MSP ID: %w", err)
+	}
+	if msp != "Org1MSP" {
+		return nil, fmt.Errorf("only Org1 can build inclusion proofs")
+	}
+
+	ckpt, err := s.GetCheckpoint(ctx, checkpointIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	credIDs, leaves, err := s.checkpointLeaves(ctx)
+	if err != nil {
+		return nil, err
+	}
+	idx := -1
+	for i, id := range credIDs {
+		if id == credID {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return nil, fmt.Errorf("credential %s not found", credID)
+	}
+
+	path, root := merkleProof(leaves, idx)
+	if root != ckpt.Root {
+		return nil, fmt.Errorf("current ledger state no longer matches checkpoint %d; publish a fresh checkpoint first", checkpointIndex)
+	}
+
+	return &InclusionProof{
+		CredID: credID,
+		Leaf:   leaves[idx],
+		Path:   path,
+		Root:   root,
+	}, nil
+}
+
+// VerifyInclusionProof recomputes the root by walking leaf up path and
+// reports whether it matches root. It takes no private data and performs no
+// state reads, so Org2 or an off-chain verifier can run the identical check
+// against a Checkpoint.Root they already trust, without needing Org1's PDC.
+func (s *SmartContract) VerifyInclusionProof(ctx contractapi.TransactionContextInterface, leaf, root string, path []MerkleProofStep) (bool, error) {
+	h := leaf
+	for _, step := range path {
+		if step.Left {
+			h = sha256Hex(step.Hash + h)
+		} else {
+			h = sha256Hex(h + step.Hash)
+		}
+	}
+	return h == root, nil
+}
+
+// ==============================
+//        Transactions
+// ==============================
+
+// IssueCredential creates Org1’s private record with an auto-computed hash.
+func (s *SmartContract) IssueCredential(ctx contractapi.TransactionContextInterface,
+	credID, studentID, studentName, university, degree, gpa, issueDate, _ string) error {
+
+	if credID == "" {
+		return fmt.Errorf("credID is required")
+	}
+	if strings.HasPrefix(credID, "salts/") {
+		// checkpointLeaves tells credentials and salt blobs apart within the
+		// same org1PDC collection by this exact prefix; a credID that collided
+		// with it would be silently skipped from every checkpoint.
+		return fmt.Errorf("credID must not start with \"salts/\"")
+	}
+	exists, err := s.CredentialExists(ctx, credID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("credential %s already exists", credID)
+	}
+
+	msp, err := ctx.GetClientIdentity().GetMSPID()
+	if err != nil {
+		return fmt.Errorf("get MSP ID: %w", err)
+	}
+
+	cred := Credential{
+		CredID:        credID,
+		StudentID:     studentID,
+		StudentName:   studentName,
+		University:    university,
+		Degree:        degree,
+		GPA:           gpa,
+		IssueDate:     issueDate,
+		Status:        "issued",
+		OwnerMSP:      msp,
+		SharedWithMSP: "",
+	}
+	cred.Hash = sha256Hex(canonicalString(&cred))
+
+	salts, err := disclosureSaltsFromTransient(ctx)
+	if err != nil {
+		return err
+	}
+	cred.MerkleRoot = disclosureRoot(&cred, salts)
+
+	saltsB, _ := json.Marshal(salts)
+	if err := s.putPDC(ctx, org1PDC, "salts/"+credID, saltsB); err != nil {
+		return err
+	}
+
+	assignment, err := nextListAssignment(ctx)
+	if err != nil {
+		return err
+	}
+	cred.ListID = assignment.ListID
+	cred.ListIndex = assignment.Index
+
+	assignmentB, _ := json.Marshal(assignment)
+	if err := ctx.GetStub().PutState(credListKey(credID), assignmentB); err != nil {
+		return fmt.Errorf("put state (cred list assignment): %w", err)
+	}
+
+	b, _ := json.Marshal(cred)
+	if err := s.putPDC(ctx, org1PDC, credID, b); err != nil {
+		return err
+	}
+	ev, err := s.putAudit(ctx, credID, "ISSUE", "", nil)
+	if err != nil {
+		return err
+	}
+	return s.emitAuditEvent(ctx, ev)
+}
+
+// IssueVerifiableCredential issues a credential exactly like IssueCredential,
+// then additionally builds its W3C Verifiable Credential JSON-LD form and a
+// detached JWT (RFC 7519) representation around it, persisting the JWT on
+// the stored Credential and the submitter's certificate in public state for
+// later verification via VerifyVC.
+//
+// Chaincode has no access to the issuer's real private key, so it cannot
+// sign the JWT itself. The caller must compute jwtSigningInput(ctx, cred, vc)
+// off-chain — deterministic from data either already on the ledger or
+// fixed by the client when it built the proposal (TxID, TxTimestamp) — sign
+// it with their real ES256 key, and pass the resulting base64url signature
+// as jwtSignature. This call only verifies that signature against the
+// submitting identity's certificate; it never fabricates one.
+func (s *SmartContract) IssueVerifiableCredential(ctx contractapi.TransactionContextInterface,
+	credID, studentID, studentName, university, degree, gpa, issueDate, jwtSignature string) (*Credential, error) {
+
+	if err := s.IssueCredential(ctx, credID, studentID, studentName, university, degree, gpa, issueDate, ""); err != nil {
+		return nil, err
+	}
+
+	cred, err := s.ReadCredential(ctx, credID)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := cid.GetX509Certificate(ctx.GetStub())
+	if err != nil {
+		return nil, fmt.Errorf("get client certificate: %w", err)
+	}
+
+	vc := &VerifiableCredential{
+		Context:      []string{"https://www.w3.org/2018/credentials/v1"},
+		Type:         []string{"VerifiableCredential", "AcademicRecordCredential"},
+		Issuer:       issuerDID(cred.OwnerMSP, cert),
+		IssuanceDate: cred.IssueDate,
+		CredentialSubject: VerifiableCredentialSubject{
+			ID:          studentDID(cred.StudentID),
+			StudentName: cred.StudentName,
+			Degree:      cred.Degree,
+			GPA:         cred.GPA,
+			University:  cred.University,
+		},
+	}
+	signingInput, err := jwtSigningInput(ctx, cred, vc)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyDetachedJWTSignature(cert, signingInput, jwtSignature); err != nil {
+		return nil, err
+	}
+	cred.JWT = signingInput + "." + jwtSignature
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	if err := ctx.GetStub().PutState("issuercert/"+cred.OwnerMSP, certPEM); err != nil {
+		return nil, fmt.Errorf("put state (issuer cert): %w", err)
+	}
+
+	b, _ := json.Marshal(cred)
+	if err := s.putPDC(ctx, org1PDC, credID, b); err != nil {
+		return nil, err
+	}
+	return cred, nil
+}
+
+// StoreCredentialForOrg2 upserts Org2's private copy (Org2 identity
+// required). It enforces that the provided hash matches the recomputed
+// canonical hash, and that saltsJSON (the same per-field disclosure salts
+// Org1 generated off-chain for IssueCredential) reproduces the credential's
+// committed MerkleRoot, before persisting both into org2PDC. Handing Org2
+// the salts leaks nothing beyond what credJSON already gives it — every
+// field is already in plaintext here — and is what lets
+// VerifyCredentialIntegrity check the Merkle commitment on Org2's side.
+func (s *SmartContract) StoreCredentialForOrg2(ctx contractapi.TransactionContextInterface, credJSON, saltsJSON string) error {
+	msp, _ := ctx.GetClientIdentity().GetMSPID()
+	if msp != "Org2MSP" {
+		return fmt.Errorf("only Org2 can write into %s", org2PDC)
+	}
+	var cred Credential
+	if err := json.Unmarshal([]byte(credJSON), &cred); err != nil {
+		return fmt.Errorf("invalid credential json: %w", err)
+	}
+	if cred.CredID == "" {
+		return fmt.Errorf("credID required in credential json")
+	}
+
+	computed := sha256Hex(canonicalString(&cred))
+	if cred.Hash == "" || cred.Hash != computed {
+		return fmt.Errorf("hash mismatch for %s: provided='%s' computed='%s'", cred.CredID, cred.Hash, computed)
+	}
+
+	var salts map[string]string
+	if err := json.Unmarshal([]byte(saltsJSON), &salts); err != nil {
+		return fmt.Errorf("invalid salts json: %w", err)
+	}
+	if root := disclosureRoot(&cred, salts); root != cred.MerkleRoot {
+		return fmt.Errorf("merkle root mismatch for %s: provided='%s' computed='%s'", cred.CredID, cred.MerkleRoot, root)
+	}
+
+	cred.SharedWithMSP = "Org2MSP"
+	b, _ := json.Marshal(cred)
+	if err := s.putPDC(ctx, org2PDC, cred.CredID, b); err != nil {
+		return err
+	}
+	saltsB, _ := json.Marshal(salts)
+	if err := s.putPDC(ctx, org2PDC, "salts/"+cred.CredID, saltsB); err != nil {
+		return err
+	}
+	ev, err := s.putAudit(ctx, cred.CredID, "SHARE_TO_ORG2", "", []string{"org2-shared"})
+	if err != nil {
+		return err
+	}
+	return s.emitAuditEvent(ctx, ev)
+}
+
+// RevokeCredential updates Org1’s private record status to "revoked" and
+// flips credID's bit in its StatusList2021 revocation bitmap.
+func (s *SmartContract) RevokeCredential(ctx contractapi.TransactionContextInterface, credID string) error {
+	msp, _ := ctx.GetClientIdentity().GetMSPID()
+	if msp != "Org1MSP" {
+		return fmt.Errorf("only Org1 can revoke credentials")
+	}
+	raw, err := s.getPDC(ctx, org1PDC, credID)
+	if err != nil {
+		return err
 	}
 	if raw == nil {
 		return fmt.Errorf("credential %s not found", credID)
@@ -315,7 +1469,68 @@ func (s *SmartContract) RevokeCredential(ctx contractapi.TransactionContextInter
 	if err := s.putPDC(ctx, org1PDC, credID, b); err != nil {
 		return err
 	}
-	return s.putAudit(ctx, credID, "REVOKE", "")
+
+	bitmap, err := loadBitmap(ctx, cred.ListID)
+	if err != nil {
+		return err
+	}
+	setBit(bitmap, cred.ListIndex)
+	blob, err := encodeBitmap(bitmap)
+	if err != nil {
+		return err
+	}
+	if err := ctx.GetStub().PutState("revlist/"+cred.ListID, blob); err != nil {
+		return fmt.Errorf("put state (revlist): %w", err)
+	}
+	revListEv, err := s.putAudit(ctx, credID, "REVLIST_UPDATE", fmt.Sprintf("listID=%s index=%d", cred.ListID, cred.ListIndex), []string{"revoked"})
+	if err != nil {
+		return err
+	}
+	if err := clearNameRecords(ctx, credID); err != nil {
+		return err
+	}
+
+	revokeEv, err := s.putAudit(ctx, credID, "REVOKE", "", []string{"revoked"})
+	if err != nil {
+		return err
+	}
+	// Fabric allows only one SetEvent per transaction, so both of this
+	// transaction's audit records are batched into a single emitted event
+	// rather than letting REVOKE silently supersede REVLIST_UPDATE.
+	return s.emitAuditEvents(ctx, revListEv, revokeEv)
+}
+
+// clearNameRecords removes every NameRecord (and any in-progress auction)
+// pointing at credID, using the credname/<credID>/<name> inverse index.
+func clearNameRecords(ctx contractapi.TransactionContextInterface, credID string) error {
+	prefix := credNamePrefix(credID)
+	iter, err := ctx.GetStub().GetStateByRange(prefix, prefix+"\uffff")
+	if err != nil {
+		return fmt.Errorf("credname range query: %w", err)
+	}
+	defer iter.Close()
+
+	names := make([]string, 0)
+	for iter.HasNext() {
+		kv, err := iter.Next()
+		if err != nil {
+			return fmt.Errorf("credname range iterate: %w", err)
+		}
+		names = append(names, strings.TrimPrefix(kv.Key, prefix))
+	}
+
+	for _, name := range names {
+		if err := ctx.GetStub().DelState(nameKey(name)); err != nil {
+			return fmt.Errorf("del state (name): %w", err)
+		}
+		if err := ctx.GetStub().DelState(nameAuctionKey(name)); err != nil {
+			return fmt.Errorf("del state (auction): %w", err)
+		}
+		if err := ctx.GetStub().DelState(credNameKey(credID, name)); err != nil {
+			return fmt.Errorf("del state (cred name index): %w", err)
+		}
+	}
+	return nil
 }
 
 // CredentialExists checks Org1’s PDC for a key.
@@ -327,6 +1542,426 @@ func (s *SmartContract) CredentialExists(ctx contractapi.TransactionContextInter
 	return raw != nil, nil
 }
 
+// PublishRevocationList ensures the active StatusList2021 bitmap exists in
+// public state, initializing it all-clear if this is its first publish.
+// RevokeCredential keeps it up to date from then on; this just returns the
+// listID so a verifier knows which ListURI to fetch.
+func (s *SmartContract) PublishRevocationList(ctx contractapi.TransactionContextInterface) (string, error) {
+	msp, _ := ctx.GetClientIdentity().GetMSPID()
+	if msp != "Org1MSP" {
+		return "", fmt.Errorf("only Org1 can publish revocation lists")
+	}
+
+	raw, err := ctx.GetStub().GetState("revlistcounter")
+	if err != nil {
+		return "", fmt.Errorf("get state (revlist counter): %w", err)
+	}
+	listID := "1"
+	if raw != nil {
+		var counter revListCounter
+		if err := json.Unmarshal(raw, &counter); err != nil {
+			return "", fmt.Errorf("unmarshal revlist counter: %w", err)
+		}
+		listID = counter.ListID
+	}
+
+	existing, err := ctx.GetStub().GetState("revlist/" + listID)
+	if err != nil {
+		return "", fmt.Errorf("get state (revlist): %w", err)
+	}
+	if existing != nil {
+		return listID, nil
+	}
+
+	blob, err := encodeBitmap(make([]byte, revListCapacityBits/8))
+	if err != nil {
+		return "", err
+	}
+	if err := ctx.GetStub().PutState("revlist/"+listID, blob); err != nil {
+		return "", fmt.Errorf("put state (revlist): %w", err)
+	}
+	return listID, nil
+}
+
+// ReserveName claims a human-readable alias for credID. Only credID's
+// owning MSP may reserve a name against it — otherwise any org could
+// squat a name on a credential it doesn't own, which is exactly what this
+// subsystem exists to prevent. If no competing BidOnName arrives within
+// nameContestWindowSeconds, the reservation becomes final for the
+// reserving MSP; a competing bid instead routes the name through a
+// sealed-bid auction settled by CommitNameAuction.
+func (s *SmartContract) ReserveName(ctx contractapi.TransactionContextInterface, name, credID string) error {
+	if name == "" {
+		return fmt.Errorf("name is required")
+	}
+	msp, err := ctx.GetClientIdentity().GetMSPID()
+	if err != nil {
+		return fmt.Errorf("get MSP ID: %w", err)
+	}
+
+	cred, err := s.ReadCredential(ctx, credID)
+	if err != nil {
+		return err
+	}
+	if msp != cred.OwnerMSP {
+		return fmt.Errorf("%s does not own credential %s", msp, credID)
+	}
+
+	existing, err := ctx.GetStub().GetState(nameKey(name))
+	if err != nil {
+		return fmt.Errorf("get state (name): %w", err)
+	}
+	if existing != nil {
+		return fmt.Errorf("name %s is already reserved", name)
+	}
+
+	now, err := ledgerClock(ctx)
+	if err != nil {
+		return err
+	}
+	record := NameRecord{
+		Name:       name,
+		CredID:     credID,
+		OwnerMSP:   msp,
+		ExpiryTime: now + nameContestWindowSeconds,
+	}
+	b, _ := json.Marshal(record)
+	if err := ctx.GetStub().PutState(nameKey(name), b); err != nil {
+		return fmt.Errorf("put state (name): %w", err)
+	}
+	if err := ctx.GetStub().PutState(credNameKey(credID, name), []byte(name)); err != nil {
+		return fmt.Errorf("put state (cred name index): %w", err)
+	}
+	return nil
+}
+
+// BidOnName commits a sealed bid for a reserved-but-still-contestable name.
+// commitment is hex(sha256(amount|salt|bidderMSP)), computed off-chain by
+// the bidder so the actual amount is never written to the ledger during the
+// commit phase; RevealBid is the only place an amount becomes visible. The
+// first commitment opens a nameAuctionWindowSeconds-long commit phase;
+// later commitments (including a replacement from the same bidder) are
+// accepted until it closes. CommitNameAuction settles the winner once
+// RevealBid's reveal phase has also closed.
+func (s *SmartContract) BidOnName(ctx contractapi.TransactionContextInterface, name, commitment string) error {
+	msp, err := ctx.GetClientIdentity().GetMSPID()
+	if err != nil {
+		return fmt.Errorf("get MSP ID: %w", err)
+	}
+	if commitment == "" {
+		return fmt.Errorf("commitment is required")
+	}
+	commitBytes, err := hex.DecodeString(commitment)
+	if err != nil {
+		return fmt.Errorf("commitment must be hex-encoded: %w", err)
+	}
+
+	recordRaw, err := ctx.GetStub().GetState(nameKey(name))
+	if err != nil {
+		return fmt.Errorf("get state (name): %w", err)
+	}
+	if recordRaw == nil {
+		return fmt.Errorf("name %s is not reserved", name)
+	}
+	var record NameRecord
+	if err := json.Unmarshal(recordRaw, &record); err != nil {
+		return fmt.Errorf("unmarshal name record: %w", err)
+	}
+	if msp == record.OwnerMSP {
+		return fmt.Errorf("owner of %s cannot bid on its own name", name)
+	}
+
+	now, err := ledgerClock(ctx)
+	if err != nil {
+		return err
+	}
+
+	auctionRaw, err := ctx.GetStub().GetState(nameAuctionKey(name))
+	if err != nil {
+		return fmt.Errorf("get state (auction): %w", err)
+	}
+	var auction NameAuction
+	if auctionRaw == nil {
+		if now > record.ExpiryTime {
+			return fmt.Errorf("name %s is already final", name)
+		}
+		auction = NameAuction{
+			Name:           name,
+			CloseTime:      now + nameAuctionWindowSeconds,
+			RevealDeadline: now + nameAuctionWindowSeconds + nameRevealWindowSeconds,
+			Commitments:    map[string][]byte{},
+			Revealed:       map[string]uint64{},
+		}
+	} else {
+		if err := json.Unmarshal(auctionRaw, &auction); err != nil {
+			return fmt.Errorf("unmarshal auction: %w", err)
+		}
+		if now > auction.CloseTime {
+			return fmt.Errorf("commit phase for %s has already closed", name)
+		}
+	}
+	auction.Commitments[msp] = commitBytes
+
+	b, _ := json.Marshal(auction)
+	if err := ctx.GetStub().PutState(nameAuctionKey(name), b); err != nil {
+		return fmt.Errorf("put state (auction): %w", err)
+	}
+	return nil
+}
+
+// RevealBid discloses a bidder's actual amount once the commit phase has
+// closed, checking it against the sha256(amount|salt|bidderMSP) commitment
+// that bidder recorded via BidOnName. A reveal that doesn't match its own
+// commitment is rejected outright rather than silently ignored, since a
+// mismatched reveal almost always means the bidder lost their salt or is
+// attempting to claim a bid they never actually committed to.
+func (s *SmartContract) RevealBid(ctx contractapi.TransactionContextInterface, name string, amount uint64, salt string) error {
+	msp, err := ctx.GetClientIdentity().GetMSPID()
+	if err != nil {
+		return fmt.Errorf("get MSP ID: %w", err)
+	}
+
+	auctionRaw, err := ctx.GetStub().GetState(nameAuctionKey(name))
+	if err != nil {
+		return fmt.Errorf("get state (auction): %w", err)
+	}
+	if auctionRaw == nil {
+		return fmt.Errorf("no auction in progress for %s", name)
+	}
+	var auction NameAuction
+	if err := json.Unmarshal(auctionRaw, &auction); err != nil {
+		return fmt.Errorf("unmarshal auction: %w", err)
+	}
+
+	now, err := ledgerClock(ctx)
+	if err != nil {
+		return err
+	}
+	if now <= auction.CloseTime {
+		return fmt.Errorf("commit phase for %s is still open", name)
+	}
+	if now > auction.RevealDeadline {
+		return fmt.Errorf("reveal window for %s has closed", name)
+	}
+
+	commitment, ok := auction.Commitments[msp]
+	if !ok {
+		return fmt.Errorf("%s has no commitment for %s", msp, name)
+	}
+	want := sha256.Sum256([]byte(fmt.Sprintf("%d|%s|%s", amount, salt, msp)))
+	if !bytes.Equal(commitment, want[:]) {
+		return fmt.Errorf("revealed bid does not match %s's commitment for %s", msp, name)
+	}
+
+	auction.Revealed[msp] = amount
+	if auction.HighestBidMSP == "" || amount > auction.HighestBid {
+		auction.HighestBid = amount
+		auction.HighestBidMSP = msp
+	}
+
+	b, _ := json.Marshal(auction)
+	if err := ctx.GetStub().PutState(nameAuctionKey(name), b); err != nil {
+		return fmt.Errorf("put state (auction): %w", err)
+	}
+	return nil
+}
+
+// CommitNameAuction settles an auction whose reveal window has closed,
+// transferring ownership of name to whichever bidder revealed the highest
+// amount. A commitment that was never revealed cannot win, since its amount
+// was never actually proven; if nobody revealed at all, the name instead
+// falls back to its original reserver (as an uncontested ReserveName would
+// have) rather than being left stuck with no settlement path.
+func (s *SmartContract) CommitNameAuction(ctx contractapi.TransactionContextInterface, name string) error {
+	auctionRaw, err := ctx.GetStub().GetState(nameAuctionKey(name))
+	if err != nil {
+		return fmt.Errorf("get state (auction): %w", err)
+	}
+	if auctionRaw == nil {
+		return fmt.Errorf("no auction in progress for %s", name)
+	}
+	var auction NameAuction
+	if err := json.Unmarshal(auctionRaw, &auction); err != nil {
+		return fmt.Errorf("unmarshal auction: %w", err)
+	}
+
+	now, err := ledgerClock(ctx)
+	if err != nil {
+		return err
+	}
+	if now <= auction.RevealDeadline {
+		return fmt.Errorf("reveal window for %s closes at %d, current time is %d", name, auction.RevealDeadline, now)
+	}
+
+	recordRaw, err := ctx.GetStub().GetState(nameKey(name))
+	if err != nil {
+		return fmt.Errorf("get state (name): %w", err)
+	}
+	if recordRaw == nil {
+		return fmt.Errorf("name %s is not reserved", name)
+	}
+	var record NameRecord
+	if err := json.Unmarshal(recordRaw, &record); err != nil {
+		return fmt.Errorf("unmarshal name record: %w", err)
+	}
+
+	// Nobody revealed (BidOnName takes no deposit, so a bidder who commits
+	// and then simply never reveals costs themselves nothing). There's no
+	// honest winner to settle on, so the name finalizes for its original
+	// reserver instead — exactly as an uncontested ReserveName would have —
+	// rather than leaving the auction in place to brick the name forever:
+	// BidOnName would keep seeing a closed commit phase, CommitNameAuction
+	// would keep landing here, and ReserveName would keep seeing
+	// name/<name> already taken.
+	if len(auction.Revealed) > 0 {
+		record.OwnerMSP = auction.HighestBidMSP
+	}
+	record.ExpiryTime = 0
+
+	b, _ := json.Marshal(record)
+	if err := ctx.GetStub().PutState(nameKey(name), b); err != nil {
+		return fmt.Errorf("put state (name): %w", err)
+	}
+	if err := ctx.GetStub().DelState(nameAuctionKey(name)); err != nil {
+		return fmt.Errorf("del state (auction): %w", err)
+	}
+	return nil
+}
+
+// checkpointLeaves reads every credential Org1 has issued by range-scanning
+// org1PDC directly (skipping the "salts/<credID>" entries interleaved in the
+// same collection) and returns their credIDs in sorted order alongside the
+// matching checkpoint leaf hashes, ready for merkleRoot/merkleProof. Its
+// only callers are PublishCheckpoint's proposal step and ProveInclusion,
+// both of which already restrict the caller to Org1, so scanning org1PDC
+// instead of a public index doesn't need a separate permission check here.
+// Using org1PDC (rather than any public-state index) also means the full
+// credID list this builds is never exposed to anyone outside Org1.
+func (s *SmartContract) checkpointLeaves(ctx contractapi.TransactionContextInterface) ([]string, []string, error) {
+	iter, err := ctx.GetStub().GetPrivateDataByRange(org1PDC, "", "")
+	if err != nil {
+		return nil, nil, fmt.Errorf("org1 private data range query: %w", err)
+	}
+	defer iter.Close()
+
+	byID := make(map[string]Credential)
+	for iter.HasNext() {
+		kv, err := iter.Next()
+		if err != nil {
+			return nil, nil, fmt.Errorf("org1 private data range iterate: %w", err)
+		}
+		if strings.HasPrefix(kv.Key, "salts/") {
+			continue
+		}
+		var cred Credential
+		if err := json.Unmarshal(kv.Value, &cred); err != nil {
+			return nil, nil, fmt.Errorf("unmarshal credential: %w", err)
+		}
+		byID[cred.CredID] = cred
+	}
+
+	credIDs := make([]string, 0, len(byID))
+	for credID := range byID {
+		credIDs = append(credIDs, credID)
+	}
+	sort.Strings(credIDs)
+
+	leaves := make([]string, 0, len(credIDs))
+	for _, credID := range credIDs {
+		cred := byID[credID]
+		leaves = append(leaves, checkpointLeafHash(cred.CredID, cred.Hash, cred.Status))
+	}
+	return credIDs, leaves, nil
+}
+
+// PublishCheckpoint is Org1's and Org2's shared multisig gate for anchoring a
+// new checkpoint: Org1 (the only MSP with org1PDC access) proposes the root
+// by calling this once, then each other designatedCheckpointSigners MSP
+// calls it again to countersign the same pending proposal. The signed
+// proposal bytes GetSignedProposal exposes for the calling identity are
+// recorded as that signer's attestation. Once checkpointThreshold signers
+// have countersigned, the checkpoint is finalized under "ckpt/<index>" and
+// the pending record is cleared; until then it returns an error reporting
+// how many more signers are needed.
+func (s *SmartContract) PublishCheckpoint(ctx contractapi.TransactionContextInterface) (*Checkpoint, error) {
+	msp, err := ctx.GetClientIdentity().GetMSPID()
+	if err != nil {
+		return nil, fmt.Errorf("get MSP ID: %w", err)
+	}
+	if !isDesignatedCheckpointSigner(msp) {
+		return nil, fmt.Errorf("%s is not a designated checkpoint signer", msp)
+	}
+	sp, err := ctx.GetStub().GetSignedProposal()
+	if err != nil {
+		return nil, fmt.Errorf("get signed proposal: %w", err)
+	}
+
+	pendingRaw, err := ctx.GetStub().GetState("ckptpending")
+	if err != nil {
+		return nil, fmt.Errorf("get state (checkpoint pending): %w", err)
+	}
+
+	var pending pendingCheckpoint
+	if pendingRaw == nil {
+		if msp != "Org1MSP" {
+			return nil, fmt.Errorf("only Org1 can propose a new checkpoint")
+		}
+		_, leaves, err := s.checkpointLeaves(ctx)
+		if err != nil {
+			return nil, err
+		}
+		index, err := nextCheckpointIndex(ctx)
+		if err != nil {
+			return nil, err
+		}
+		height, err := ledgerClock(ctx)
+		if err != nil {
+			return nil, err
+		}
+		pending = pendingCheckpoint{
+			Index:       index,
+			Root:        merkleRoot(leaves),
+			BlockHeight: height,
+			Signatures:  map[string][]byte{},
+		}
+	} else if err := json.Unmarshal(pendingRaw, &pending); err != nil {
+		return nil, fmt.Errorf("unmarshal checkpoint pending: %w", err)
+	}
+
+	pending.Signatures[msp] = sp.Signature
+
+	if len(pending.Signatures) < checkpointThreshold {
+		b, _ := json.Marshal(pending)
+		if err := ctx.GetStub().PutState("ckptpending", b); err != nil {
+			return nil, fmt.Errorf("put state (checkpoint pending): %w", err)
+		}
+		return nil, fmt.Errorf("checkpoint %d awaiting %d more signer(s)", pending.Index, checkpointThreshold-len(pending.Signatures))
+	}
+
+	signers := make([]string, 0, len(pending.Signatures))
+	for m := range pending.Signatures {
+		signers = append(signers, m)
+	}
+	sort.Strings(signers)
+
+	ts, _ := ctx.GetStub().GetTxTimestamp()
+	ckpt := Checkpoint{
+		Index:       pending.Index,
+		Root:        pending.Root,
+		BlockHeight: pending.BlockHeight,
+		Timestamp:   time.Unix(ts.GetSeconds(), int64(ts.GetNanos())).UTC().Format(time.RFC3339),
+		SignersMSP:  signers,
+	}
+	b, _ := json.Marshal(ckpt)
+	if err := ctx.GetStub().PutState(fmt.Sprintf("ckpt/%d", ckpt.Index), b); err != nil {
+		return nil, fmt.Errorf("put state (checkpoint): %w", err)
+	}
+	if err := ctx.GetStub().DelState("ckptpending"); err != nil {
+		return nil, fmt.Errorf("del state (checkpoint pending): %w", err)
+	}
+	return &ckpt, nil
+}
+
 // ==============================
 //             Main
 // ==============================
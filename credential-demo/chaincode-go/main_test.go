@@ -0,0 +1,203 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// selfSignedECDSACert generates a throwaway ES256 key and a matching
+// self-signed certificate, mirroring the ECDSA issuer certs this chaincode
+// expects to find under "issuercert/<OwnerMSP>".
+func selfSignedECDSACert(t *testing.T) (*ecdsa.PrivateKey, *x509.Certificate) {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test-issuer"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &priv.PublicKey, priv)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+	return priv, cert
+}
+
+// signDetached signs signingInput the way an off-chain issuer is expected
+// to before submitting IssueVerifiableCredential: raw R||S, base64url.
+func signDetached(t *testing.T, priv *ecdsa.PrivateKey, signingInput string) string {
+	t.Helper()
+	digest := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, priv, digest[:])
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	rb := r.FillBytes(make([]byte, 32))
+	sb := s.FillBytes(make([]byte, 32))
+	return base64.RawURLEncoding.EncodeToString(append(rb, sb...))
+}
+
+func TestVerifyDetachedJWTSignature(t *testing.T) {
+	priv, cert := selfSignedECDSACert(t)
+	signingInput := "header.payload"
+	sig := signDetached(t, priv, signingInput)
+
+	if err := verifyDetachedJWTSignature(cert, signingInput, sig); err != nil {
+		t.Fatalf("expected valid signature to verify, got: %v", err)
+	}
+
+	if err := verifyDetachedJWTSignature(cert, "tampered.payload", sig); err == nil {
+		t.Fatal("expected signature verification to fail over a tampered signing input")
+	}
+
+	otherPriv, _ := selfSignedECDSACert(t)
+	wrongSig := signDetached(t, otherPriv, signingInput)
+	if err := verifyDetachedJWTSignature(cert, signingInput, wrongSig); err == nil {
+		t.Fatal("expected signature verification to fail for a signature from a different key")
+	}
+}
+
+// TestDisclosureRootRoundTrip exercises the selective-disclosure Merkle tree
+// the way GenerateDisclosureProof/VerifyDisclosureProof do: reveal some
+// fields, carry the rest only as leaf hashes, and confirm the root rebuilt
+// from that mix matches the one committed over every field up front.
+func TestDisclosureRootRoundTrip(t *testing.T) {
+	cred := &Credential{
+		CredID:      "cred-1",
+		StudentID:   "stu-1",
+		StudentName: "Alice",
+		University:  "MIT",
+		Degree:      "BSc",
+		GPA:         "3.9",
+		IssueDate:   "2026-01-01",
+	}
+	salts := make(map[string]string, len(disclosureFieldOrder))
+	for i, name := range disclosureFieldOrder {
+		salts[name] = sha256Hex(fmt.Sprintf("salt-%d", i))
+	}
+	committedRoot := disclosureRoot(cred, salts)
+
+	// Rebuild the leaf set the way VerifyDisclosureProof does from a
+	// GenerateDisclosureProof-shaped proof: revealed fields recompute their
+	// leaf hash from (value, salt); undisclosed fields are taken as-is from
+	// their pre-hashed MerkleSibling. The resulting root must still match
+	// what disclosureRoot committed over every field up front.
+	revealed := map[string]bool{"studentName": true, "degree": true}
+	leaves := make([]string, len(disclosureFieldOrder))
+	for i, name := range disclosureFieldOrder {
+		if revealed[name] {
+			continue
+		}
+		value, _ := disclosureFieldValue(cred, name)
+		leaves[i] = disclosureLeafHash(name, value, salts[name]) // sibling, from GenerateDisclosureProof
+	}
+	for name := range revealed {
+		i := disclosureFieldIndex(name)
+		value, _ := disclosureFieldValue(cred, name)
+		leaves[i] = disclosureLeafHash(name, value, salts[name]) // revealed field, from RevealedField{Value, Salt}
+	}
+	rebuiltRoot := merkleRoot(leaves)
+
+	if rebuiltRoot != committedRoot {
+		t.Fatalf("rebuilt root %q does not match committed root %q", rebuiltRoot, committedRoot)
+	}
+
+	// Changing one revealed value must change the root: a verifier rebuilding
+	// from a tampered disclosure proof should never land on the same root.
+	tampered := make([]string, len(leaves))
+	copy(tampered, leaves)
+	tampered[disclosureFieldIndex("studentName")] = disclosureLeafHash("studentName", "Mallory", salts["studentName"])
+	if merkleRoot(tampered) == committedRoot {
+		t.Fatal("expected tampering a revealed field to change the root")
+	}
+}
+
+// TestRevocationBitmapRoundTrip covers the StatusList2021 bitmap ops
+// RevokeCredential and IsRevoked rely on: setting/reading individual bits
+// and the gzip+base64 encoding used to persist the bitmap in public state.
+func TestRevocationBitmapRoundTrip(t *testing.T) {
+	bitmap := make([]byte, revListCapacityBits/8)
+	indices := []int{0, 1, 7, 8, 63, 131071}
+	for _, idx := range indices {
+		setBit(bitmap, idx)
+	}
+	for _, idx := range indices {
+		if !getBit(bitmap, idx) {
+			t.Fatalf("expected bit %d to be set", idx)
+		}
+	}
+	// an adjacent, never-set bit must stay clear
+	if getBit(bitmap, 64) {
+		t.Fatal("expected bit 64 to remain clear")
+	}
+
+	encoded, err := encodeBitmap(bitmap)
+	if err != nil {
+		t.Fatalf("encodeBitmap: %v", err)
+	}
+	decoded, err := decodeBitmap(encoded)
+	if err != nil {
+		t.Fatalf("decodeBitmap: %v", err)
+	}
+	for _, idx := range indices {
+		if !getBit(decoded, idx) {
+			t.Fatalf("expected bit %d to survive encode/decode round trip", idx)
+		}
+	}
+	if getBit(decoded, 64) {
+		t.Fatal("expected bit 64 to remain clear after round trip")
+	}
+}
+
+// TestMerkleProofMatchesMerkleRoot covers the audit-path construction
+// ProveInclusion relies on: for every leaf, merkleProof's path must
+// recompute to the same root merkleRoot derives directly from the leaf set
+// (including the odd-length-level duplication rule both functions share),
+// and a tampered leaf must fail VerifyInclusionProof's walk.
+func TestMerkleProofMatchesMerkleRoot(t *testing.T) {
+	leaves := []string{
+		checkpointLeafHash("cred-1", "hash-1", "issued"),
+		checkpointLeafHash("cred-2", "hash-2", "issued"),
+		checkpointLeafHash("cred-3", "hash-3", "revoked"),
+	}
+	root := merkleRoot(leaves)
+
+	for i, leaf := range leaves {
+		path, gotRoot := merkleProof(leaves, i)
+		if gotRoot != root {
+			t.Fatalf("merkleProof root for leaf %d = %q, want %q", i, gotRoot, root)
+		}
+		ok, err := (&SmartContract{}).VerifyInclusionProof(nil, leaf, root, path)
+		if err != nil {
+			t.Fatalf("VerifyInclusionProof: %v", err)
+		}
+		if !ok {
+			t.Fatalf("expected leaf %d's path to verify against root", i)
+		}
+
+		tamperedOK, err := (&SmartContract{}).VerifyInclusionProof(nil, "tampered-leaf", root, path)
+		if err != nil {
+			t.Fatalf("VerifyInclusionProof: %v", err)
+		}
+		if tamperedOK {
+			t.Fatalf("expected a tampered leaf to fail verification at index %d", i)
+		}
+	}
+}